@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// cmd_mount times how long "rfs mount" takes to make a flist's content
+// available at mount_point, then unmounts it again.
+func cmd_mount(args []string) {
+	fs := flag.NewFlagSet("mount", flag.ExitOnError)
+
+	var rfs_bin string
+	fs.StringVar(&rfs_bin, "rfs_bin", "rfs", "Path to the rfs binary")
+
+	var meta_path string
+	fs.StringVar(&meta_path, "meta", "/tmp/bench.fl", "Flist (.fl) to mount")
+
+	var mount_point string
+	fs.StringVar(&mount_point, "mountpoint", "/tmp/bench_mnt", "Directory to mount onto")
+
+	var timeout time.Duration
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "Max time to wait for the mount to become ready")
+
+	var log_path string
+	fs.StringVar(&log_path, "lpath", "/tmp/mount_log", "File path")
+
+	fs.Parse(args)
+
+	log_file, _ := os.Create(log_path)
+	defer log_file.Close()
+
+	if err := os.MkdirAll(mount_point, 0755); err != nil {
+		panic(fmt.Sprintln("Err: ", err))
+	}
+
+	cmd := exec.Command(rfs_bin, "mount", "-m", meta_path, mount_point)
+	before_mount := time.Now()
+	if err := cmd.Start(); err != nil {
+		panic(fmt.Sprintln("Err: ", err))
+	}
+	// Guarantee the mount is torn down even if we panic below (e.g. the
+	// ready-wait times out), so a failed run doesn't leave a live mount.
+	defer func() {
+		exec.Command("umount", mount_point).Run()
+		cmd.Wait()
+	}()
+
+	deadline := time.Now().Add(timeout)
+	for !mount_ready(mount_point) {
+		if time.Now().After(deadline) {
+			panic(fmt.Sprintln("Err: mount did not become ready within", timeout))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	elapsed := time.Since(before_mount)
+
+	sout := fmt.Sprintf("======[mount | %s]=========\r\nTime to ready: %s\r\n", meta_path, elapsed)
+	fmt.Print(sout)
+	log_file.WriteString(sout)
+}
+
+// mount_ready reports whether mount_point already has the mounted flist's
+// content visible (i.e. is no longer an empty directory).
+func mount_ready(mount_point string) bool {
+	entries, err := os.ReadDir(mount_point)
+	if err != nil {
+		return false
+	}
+	return len(entries) > 0
+}
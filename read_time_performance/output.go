@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	format_text = "text"
+	format_csv  = "csv"
+	format_json = "json"
+)
+
+// read_record describes a single block read, as emitted by -format csv/json.
+type read_record struct {
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp_us"`
+	Offset    uint64 `json:"offset"`
+	Size      int    `json:"size"`
+	LatencyUs uint   `json:"latency_us"`
+}
+
+// read_summary is the final record of a benchmark run: percentiles and
+// throughput rather than a single block's timing.
+type read_summary struct {
+	Type           string  `json:"type"`
+	Mode           string  `json:"mode"`
+	Concurrency    uint    `json:"concurrency"`
+	Iterations     uint    `json:"iterations"`
+	BlockSize      uint    `json:"block_size"`
+	P50Us          uint    `json:"p50_us"`
+	P90Us          uint    `json:"p90_us"`
+	P95Us          uint    `json:"p95_us"`
+	P99Us          uint    `json:"p99_us"`
+	ThroughputMiBs float64 `json:"throughput_mibs"`
+	TotalBytes     uint64  `json:"total_bytes"`
+	WallTime       string  `json:"wall_time"`
+}
+
+// write_records emits one line per block read plus a final summary line to
+// both stdout and log_file, in the requested format. This makes the
+// benchmark scriptable in CI so regressions in the read path can be tracked
+// over time, instead of requiring a human to eyeball the log file.
+func write_records(log_file *os.File, format string, records []read_record, summary read_summary) {
+	switch format {
+	case format_csv:
+		write_csv(log_file, records, summary)
+	case format_json:
+		write_json(log_file, records, summary)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown -format %q, falling back to csv\r\n", format)
+		write_csv(log_file, records, summary)
+	}
+}
+
+func write_csv(log_file *os.File, records []read_record, summary read_summary) {
+	header := "type,timestamp_us,offset,size,latency_us,p50_us,p90_us,p95_us,p99_us,throughput_mibs,total_bytes,wall_time\r\n"
+	fmt.Print(header)
+	log_file.WriteString(header)
+
+	for _, r := range records {
+		line := fmt.Sprintf("%s,%d,%d,%d,%d,,,,,,,\r\n", r.Type, r.Timestamp, r.Offset, r.Size, r.LatencyUs)
+		fmt.Print(line)
+		log_file.WriteString(line)
+	}
+
+	line := fmt.Sprintf("%s,,,,,%d,%d,%d,%d,%.2f,%d,%s\r\n", summary.Type, summary.P50Us, summary.P90Us, summary.P95Us, summary.P99Us, summary.ThroughputMiBs, summary.TotalBytes, summary.WallTime)
+	fmt.Print(line)
+	log_file.WriteString(line)
+}
+
+func write_json(log_file *os.File, records []read_record, summary read_summary) {
+	for _, r := range records {
+		line, _ := json.Marshal(r)
+		fmt.Println(string(line))
+		log_file.Write(line)
+		log_file.WriteString("\n")
+	}
+
+	line, _ := json.Marshal(summary)
+	fmt.Println(string(line))
+	log_file.Write(line)
+	log_file.WriteString("\n")
+}
+
+// histogram_bucket is one HdrHistogram-style cumulative bucket: the number
+// of samples at or below upper_us.
+type histogram_bucket struct {
+	upper_us uint
+	count    uint
+}
+
+// write_histogram writes a power-of-two bucketed, cumulative latency
+// histogram to path, in "le_<upper_us>us <count>" lines. A blank path is a
+// no-op, since -histogram is optional.
+func write_histogram(path string, latencies []uint) error {
+	if path == "" || len(latencies) == 0 {
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	max_latency := uint(0)
+	for _, l := range latencies {
+		if l > max_latency {
+			max_latency = l
+		}
+	}
+
+	for upper := uint(1); ; upper *= 2 {
+		count := uint(0)
+		for _, l := range latencies {
+			if l <= upper {
+				count++
+			}
+		}
+		fmt.Fprintf(f, "le_%dus %d\n", upper, count)
+		if upper >= max_latency {
+			break
+		}
+	}
+	return nil
+}
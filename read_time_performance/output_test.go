@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteHistogramEmptyPath(t *testing.T) {
+	if err := write_histogram("", []uint{1, 2, 3}); err != nil {
+		t.Fatalf("write_histogram with empty path should be a no-op, got err: %v", err)
+	}
+}
+
+func TestWriteHistogramBuckets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hist.txt")
+	latencies := []uint{1, 2, 3, 5, 9}
+
+	if err := write_histogram(path, latencies); err != nil {
+		t.Fatalf("write_histogram: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open histogram file: %v", err)
+	}
+	defer f.Close()
+
+	counts := map[string]uint{}
+	scanner := bufio.NewScanner(f)
+	var lastBucket string
+	for scanner.Scan() {
+		var bucket string
+		var count uint
+		if _, err := fmt.Sscanf(scanner.Text(), "%s %d", &bucket, &count); err != nil {
+			t.Fatalf("unexpected line %q: %v", scanner.Text(), err)
+		}
+		counts[bucket] = count
+		lastBucket = bucket
+	}
+
+	// the last (largest) bucket must cover every sample
+	if counts[lastBucket] != uint(len(latencies)) {
+		t.Errorf("final bucket count = %d, want %d (all samples)", counts[lastBucket], len(latencies))
+	}
+	// the smallest bucket (le_1us) should only cover the one sample <= 1
+	if counts["le_1us"] != 1 {
+		t.Errorf("le_1us count = %d, want 1", counts["le_1us"])
+	}
+}
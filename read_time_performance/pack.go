@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// cmd_pack times "rfs pack" of src against a configured store (zdb/dir/s3),
+// so the different backend stores can be compared head-to-head rather than
+// only measuring raw FUSE read latency.
+func cmd_pack(args []string) {
+	fs := flag.NewFlagSet("pack", flag.ExitOnError)
+
+	var rfs_bin string
+	fs.StringVar(&rfs_bin, "rfs_bin", "rfs", "Path to the rfs binary")
+
+	var src_dir string
+	fs.StringVar(&src_dir, "src", ".", "Directory tree to pack")
+
+	var meta_path string
+	fs.StringVar(&meta_path, "meta", "/tmp/bench.fl", "Output flist (.fl) path")
+
+	var store string
+	fs.StringVar(&store, "store", "", "Store spec passed to 'rfs pack -s' (e.g. zdb://.., dir://.., s3://..)")
+
+	var log_path string
+	fs.StringVar(&log_path, "lpath", "/tmp/pack_log", "File path")
+
+	fs.Parse(args)
+
+	log_file, _ := os.Create(log_path)
+	defer log_file.Close()
+
+	pack_args := []string{"pack", "-m", meta_path, "-s", store, src_dir}
+	before_pack := time.Now()
+	out, err := exec.Command(rfs_bin, pack_args...).CombinedOutput()
+	elapsed := time.Since(before_pack)
+	if err != nil {
+		panic(fmt.Sprintln("Err: ", err, string(out)))
+	}
+
+	sout := fmt.Sprintf("======[pack | store=%s]=========\r\nDuration: %s\r\n", store, elapsed)
+	fmt.Print(sout)
+	log_file.WriteString(sout)
+}
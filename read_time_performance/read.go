@@ -0,0 +1,289 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	mode_sequential          = "sequential"
+	mode_random              = "random"
+	mode_sequential_parallel = "sequential-parallel"
+)
+
+// worker_result carries one goroutine's share of the benchmark back to main
+// over a channel, so the final summary can aggregate across all workers.
+type worker_result struct {
+	iterations    uint
+	read_time_sum uint
+	records       []read_record
+	bytes_read    uint64
+}
+
+// cmd_read benchmarks reading fpath under the given mode/concurrency and
+// reports min/max/average latency, p50/p90/p95/p99 and MiB/s throughput.
+func cmd_read(args []string) {
+	fs := flag.NewFlagSet("read", flag.ExitOnError)
+
+	var block_size uint
+	fs.UintVar(&block_size, "bsize", 100, "Block size to read")
+
+	var read_repetition uint
+	fs.UintVar(&read_repetition, "repeat", 1, "Read repetition (per worker)")
+
+	var duration time.Duration
+	fs.DurationVar(&duration, "duration", 0, "Run the benchmark for this long instead of a fixed --repeat count (e.g. 30s, 5m). Overrides --repeat when non-zero")
+
+	var file_size uint64
+	fs.Uint64Var(&file_size, "file_size", 0, "Bound reads to at most this many bytes instead of the whole file: caps each sequential iteration, and caps the offset range random mode samples from (0 = use the whole file). Useful against large FUSE-mounted rfs files")
+
+	var file_path string
+	fs.StringVar(&file_path, "fpath", ".", "File path")
+
+	var log_path string
+	fs.StringVar(&log_path, "lpath", "/tmp/read_log", "File path")
+
+	var concurrency uint
+	fs.UintVar(&concurrency, "c", 1, "Number of concurrent readers")
+
+	var mode string
+	fs.StringVar(&mode, "mode", mode_sequential, "Read pattern: sequential, random or sequential-parallel")
+
+	var format string
+	fs.StringVar(&format, "format", format_text, "Output format: text, csv or json. csv/json emit one record per block read plus a final summary record")
+
+	var histogram_path string
+	fs.StringVar(&histogram_path, "histogram", "", "If set, write an HdrHistogram-style bucketed latency histogram to this path")
+
+	fs.Parse(args)
+
+	if mode == mode_sequential {
+		concurrency = 1
+	}
+
+	var target_size int64
+	if mode == mode_random {
+		info, err := os.Stat(file_path)
+		if err != nil {
+			panic(fmt.Sprintln("Err: ", err))
+		}
+		target_size = info.Size()
+		if file_size > 0 && int64(file_size) < target_size {
+			target_size = int64(file_size)
+		}
+	}
+
+	log_file, _ := os.Create(log_path)
+	defer log_file.Close()
+	var log_mu sync.Mutex
+
+	deadline := time.Now().Add(duration)
+	bench_start := time.Now()
+
+	results := make(chan worker_result, concurrency)
+	var wg sync.WaitGroup
+	for worker_id := uint(0); worker_id < concurrency; worker_id++ {
+		wg.Add(1)
+		go func(worker_id uint) {
+			defer wg.Done()
+			var res worker_result
+			switch mode {
+			case mode_random:
+				res = run_random_worker(worker_id, file_path, block_size, read_repetition, duration, deadline, target_size)
+			default:
+				res = run_sequential_worker(file_path, block_size, file_size, read_repetition, duration, deadline, format, log_file, &log_mu)
+			}
+			results <- res
+		}(worker_id)
+	}
+	wg.Wait()
+	close(results)
+
+	repetition_avg := uint(0)
+	min_read := ^uint(0)
+	max_read := uint(0)
+	var all_records []read_record
+	var total_bytes uint64
+	var iterations uint
+	for res := range results {
+		all_records = append(all_records, res.records...)
+		total_bytes += res.bytes_read
+		iterations += res.iterations
+		for _, r := range res.records {
+			repetition_avg += r.LatencyUs
+			if r.LatencyUs < min_read {
+				min_read = r.LatencyUs
+			}
+			if r.LatencyUs > max_read {
+				max_read = r.LatencyUs
+			}
+		}
+	}
+	wall_time := time.Since(bench_start)
+	if iterations == 0 {
+		iterations = 1
+	}
+	block_count := uint(len(all_records))
+	if block_count == 0 {
+		block_count = 1
+	}
+	repetition_avg /= block_count
+
+	all_latencies := make([]uint, len(all_records))
+	for i, r := range all_records {
+		all_latencies[i] = r.LatencyUs
+	}
+
+	throughput_mibs := float64(total_bytes) / (1024 * 1024) / wall_time.Seconds()
+	p50, p90, p95, p99 := latency_percentiles(all_latencies)
+
+	if err := write_histogram(histogram_path, all_latencies); err != nil {
+		panic(fmt.Sprintln("Err: ", err))
+	}
+
+	if format != format_text {
+		summary := read_summary{
+			Type:           "summary",
+			Mode:           mode,
+			Concurrency:    concurrency,
+			Iterations:     iterations,
+			BlockSize:      block_size,
+			P50Us:          p50,
+			P90Us:          p90,
+			P95Us:          p95,
+			P99Us:          p99,
+			ThroughputMiBs: throughput_mibs,
+			TotalBytes:     total_bytes,
+			WallTime:       wall_time.String(),
+		}
+		for i := range all_records {
+			all_records[i].Type = "block"
+		}
+		write_records(log_file, format, all_records, summary)
+		return
+	}
+
+	sout := fmt.Sprintf("======[mode=%s | %d worker(s) | %d reads | %d blocks | %d Bytes block size]=========\r\nAverage block read time : %d us (%.2f ms)\r\nMin block read time: %d us (%.2f ms)\r\nMax block read time: %d us (%.2f ms)\r\n\r\n", mode, concurrency, iterations, block_count, block_size, repetition_avg, float32(repetition_avg)/1000.0, min_read, float32(min_read)/1000.0, max_read, float32(max_read)/1000.0)
+	fmt.Print(sout)
+	log_file.WriteString(sout)
+
+	psout := fmt.Sprintf("50th ptile: %d us\r\n90th ptile: %d us\r\n95th ptile: %d us\r\n99th ptile: %d us\r\nThroughput: %.2f MiB/s (%d bytes in %s)\r\n", p50, p90, p95, p99, throughput_mibs, total_bytes, wall_time)
+	fmt.Print(psout)
+	log_file.WriteString(psout)
+}
+
+// latency_percentiles returns the p50/p90/p95/p99 latencies (in
+// microseconds) from the given sample set. It sorts a copy of latencies, so
+// the caller's slice is left untouched.
+func latency_percentiles(latencies []uint) (p50, p90, p95, p99 uint) {
+	if len(latencies) == 0 {
+		return 0, 0, 0, 0
+	}
+
+	sorted := make([]uint, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p float64) uint {
+		idx := int(p * float64(len(sorted)-1))
+		return sorted[idx]
+	}
+
+	return percentile(0.50), percentile(0.90), percentile(0.95), percentile(0.99)
+}
+
+// run_sequential_worker repeats a full (optionally bounded) sequential read
+// of file_path, either read_repetition times or until deadline when duration
+// is set, and reports progress to log_file as it goes (text format only).
+func run_sequential_worker(file_path string, block_size uint, file_size uint64, read_repetition uint, duration time.Duration, deadline time.Time, format string, log_file *os.File, log_mu *sync.Mutex) worker_result {
+	var res worker_result
+	for duration > 0 && time.Now().Before(deadline) || duration == 0 && res.iterations < read_repetition {
+		total_read_time, avg_op_time, records, bytes_read := handle_read(file_path, block_size, file_size)
+		res.read_time_sum += total_read_time
+		res.records = append(res.records, records...)
+		res.bytes_read += bytes_read
+		res.iterations++
+
+		if format == format_text {
+			sout := fmt.Sprintf("Read time (all blocks): %.3f us  (%.2f ms)\r\nAvrg time (for the blocks): %.3f us\r\n\r\n", float32(total_read_time), float32(total_read_time)/1000.0, avg_op_time)
+			log_mu.Lock()
+			fmt.Print(sout)
+			log_file.WriteString(sout)
+			log_mu.Unlock()
+		}
+	}
+	return res
+}
+
+// run_random_worker issues block_size ReadAt calls at uniformly random,
+// block-aligned offsets within target_size, either read_repetition times or
+// until deadline when duration is set. This exercises rfs's chunk-fetch and
+// cache path very differently from a sequential read.
+func run_random_worker(worker_id uint, file_path string, block_size uint, read_repetition uint, duration time.Duration, deadline time.Time, target_size int64) worker_result {
+	f, err := os.Open(file_path)
+	if err != nil {
+		panic(fmt.Sprintln("Err: ", err))
+	}
+	defer f.Close()
+
+	block_count := target_size / int64(block_size)
+	if block_count < 1 {
+		block_count = 1
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano() ^ int64(worker_id)))
+
+	buf := make([]byte, block_size)
+	var res worker_result
+	for duration > 0 && time.Now().Before(deadline) || duration == 0 && res.iterations < read_repetition {
+		offset := rng.Int63n(block_count) * int64(block_size)
+
+		before_read := time.Now().UnixMicro()
+		n, _ := f.ReadAt(buf, offset)
+		after_read := time.Now().UnixMicro()
+
+		block_read_time := uint(after_read - before_read)
+		res.read_time_sum += block_read_time
+		res.records = append(res.records, read_record{Timestamp: before_read, Offset: uint64(offset), Size: n, LatencyUs: block_read_time})
+		res.bytes_read += uint64(n)
+		res.iterations++
+	}
+	return res
+}
+
+func handle_read(file_path string, block_size uint, file_size uint64) (total_read_time uint, avg_op_time float32, records []read_record, bytes_read uint64) {
+	f, err := os.Open(file_path)
+
+	if err != nil {
+		panic(fmt.Sprintln("Err: ", err))
+	}
+	defer f.Close()
+
+	buf := make([]byte, block_size)
+	read_count := uint(0)
+	var before_read int64
+	var after_read int64
+	read_bytes := int(^uint(0) >> 1) //max int
+	for read_bytes > 0 {
+		if file_size > 0 && bytes_read >= file_size {
+			break
+		}
+
+		before_read = time.Now().UnixMicro()
+		read_bytes, _ = f.Read(buf)
+		after_read = time.Now().UnixMicro()
+		block_read_time := uint(after_read - before_read)
+		total_read_time += block_read_time
+		records = append(records, read_record{Timestamp: before_read, Offset: bytes_read, Size: read_bytes, LatencyUs: block_read_time})
+		bytes_read += uint64(read_bytes)
+		read_count++
+	}
+	avg_op_time = float32(total_read_time) / float32(read_count)
+
+	return
+}
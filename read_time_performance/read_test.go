@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestLatencyPercentilesEmpty(t *testing.T) {
+	p50, p90, p95, p99 := latency_percentiles(nil)
+	if p50 != 0 || p90 != 0 || p95 != 0 || p99 != 0 {
+		t.Fatalf("expected all zeros for empty input, got %d %d %d %d", p50, p90, p95, p99)
+	}
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	latencies := make([]uint, 100)
+	for i := range latencies {
+		latencies[i] = uint(i + 1) // 1..100
+	}
+
+	p50, p90, p95, p99 := latency_percentiles(latencies)
+	if p50 != 50 {
+		t.Errorf("p50 = %d, want 50", p50)
+	}
+	if p90 != 90 {
+		t.Errorf("p90 = %d, want 90", p90)
+	}
+	if p95 != 95 {
+		t.Errorf("p95 = %d, want 95", p95)
+	}
+	if p99 != 99 {
+		t.Errorf("p99 = %d, want 99", p99)
+	}
+}
+
+func TestLatencyPercentilesUnsorted(t *testing.T) {
+	latencies := []uint{30, 10, 20}
+	p50, _, _, _ := latency_percentiles(latencies)
+	if p50 != 20 {
+		t.Errorf("p50 = %d, want 20", p50)
+	}
+	// the input slice must not be mutated by the sort
+	if latencies[0] != 30 || latencies[1] != 10 || latencies[2] != 20 {
+		t.Errorf("latency_percentiles mutated its input: %v", latencies)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cmd_write benchmarks writing file_count files of file_size bytes each into
+// dir, typically an rfs-mounted directory. It reports per-file latency
+// percentiles and aggregate write throughput, mirroring cmd_read.
+func cmd_write(args []string) {
+	fs := flag.NewFlagSet("write", flag.ExitOnError)
+
+	var dir string
+	fs.StringVar(&dir, "dir", ".", "Target directory to write files into")
+
+	var file_size uint64
+	fs.Uint64Var(&file_size, "fsize", 1<<20, "Size in bytes of each file written")
+
+	var file_count uint
+	fs.UintVar(&file_count, "fcount", 10, "Number of files to write")
+
+	var block_size uint
+	fs.UintVar(&block_size, "bsize", 1<<16, "Write block size")
+
+	var log_path string
+	fs.StringVar(&log_path, "lpath", "/tmp/write_log", "File path")
+
+	fs.Parse(args)
+
+	log_file, _ := os.Create(log_path)
+	defer log_file.Close()
+
+	// Content-addressed/dedup stores (e.g. zdb) would collapse repeated
+	// identical blocks to almost nothing, making cross-store timings
+	// meaningless, so every block gets freshly generated random bytes
+	// rather than reusing one buffer's content across writes.
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	buf := make([]byte, block_size)
+
+	var latencies []uint
+	var total_bytes uint64
+	bench_start := time.Now()
+	for file_index := uint(0); file_index < file_count; file_index++ {
+		path := filepath.Join(dir, fmt.Sprintf("bench_write_%d", file_index))
+		// Remove any benchmark file we create, so repeated runs don't
+		// accumulate state in dir.
+		defer os.Remove(path)
+
+		before_write := time.Now().UnixMicro()
+		written, err := write_file(path, file_size, buf, rng)
+		after_write := time.Now().UnixMicro()
+		if err != nil {
+			panic(fmt.Sprintln("Err: ", err))
+		}
+
+		latencies = append(latencies, uint(after_write-before_write))
+		total_bytes += written
+	}
+	wall_time := time.Since(bench_start)
+
+	throughput_mibs := float64(total_bytes) / (1024 * 1024) / wall_time.Seconds()
+	p50, p90, p95, p99 := latency_percentiles(latencies)
+
+	sout := fmt.Sprintf("======[write | %d files | %d Bytes each]=========\r\n50th ptile: %d us\r\n90th ptile: %d us\r\n95th ptile: %d us\r\n99th ptile: %d us\r\nThroughput: %.2f MiB/s (%d bytes in %s)\r\n", file_count, file_size, p50, p90, p95, p99, throughput_mibs, total_bytes, wall_time)
+	fmt.Print(sout)
+	log_file.WriteString(sout)
+}
+
+// write_file writes size bytes to path in block_size chunks, refilling buf
+// with fresh random bytes from rng before every chunk so a dedup store sees
+// distinct content instead of N copies of the same block. Creates or
+// truncates the file as needed, and returns the number of bytes actually
+// written.
+func write_file(path string, size uint64, buf []byte, rng *rand.Rand) (uint64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var written uint64
+	for written < size {
+		chunk := uint64(len(buf))
+		if size-written < chunk {
+			chunk = size - written
+		}
+
+		rng.Read(buf[:chunk])
+		n, err := f.Write(buf[:chunk])
+		if err != nil {
+			return written, err
+		}
+		written += uint64(n)
+	}
+	return written, nil
+}